@@ -0,0 +1,45 @@
+package demo
+
+import (
+	"context"
+	"time"
+)
+
+// detachedContext inherits Value lookups from parent but is otherwise
+// independent: its Done/Deadline/Err come from its own base context, not
+// parent's, so canceling or finishing the originating request doesn't tear
+// down work started on a detached context.
+type detachedContext struct {
+	context.Context
+	parent context.Context
+}
+
+func (d detachedContext) Value(key any) any {
+	// Check the real base first: context.Cause (and anything else that walks
+	// the chain looking for its own cancelCtx, e.g. via an internal key) must
+	// find it on d.Context, not on parent. Only app-level values fall
+	// through to parent, since Background() never has any of its own.
+	if v := d.Context.Value(key); v != nil {
+		return v
+	}
+	return d.parent.Value(key)
+}
+
+// Detach returns a context that keeps parent's values (requestID, the
+// request logger, ...) reachable via Value, but ignores parent's
+// cancellation entirely. Use it for fire-and-forget work that must outlive
+// the request that started it.
+func Detach(parent context.Context) context.Context {
+	// base is never canceled; it is cancelable only so that context.Cause
+	// finds a cancelCtx of its own here instead of falling through to parent's.
+	base, cancel := context.WithCancel(context.Background())
+	_ = cancel
+	return detachedContext{Context: base, parent: parent}
+}
+
+// DetachWithTimeout is like Detach, but bounds the detached context with its
+// own independent timeout so fire-and-forget work can't run forever.
+func DetachWithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	base, cancel := context.WithTimeout(context.Background(), d)
+	return detachedContext{Context: base, parent: parent}, cancel
+}
@@ -0,0 +1,53 @@
+package demo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDetachPropagatesValuesNotCancellation(t *testing.T) {
+	type key string
+	parent, parentCancel := context.WithCancel(context.Background())
+	parent = context.WithValue(parent, key("foo"), "bar")
+
+	detached := Detach(parent)
+
+	if got := detached.Value(key("foo")); got != "bar" {
+		t.Fatalf("expected detached context to inherit parent value, got %v", got)
+	}
+
+	parentCancel()
+
+	select {
+	case <-detached.Done():
+		t.Fatalf("expected detached context to survive parent cancellation")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if detached.Err() != nil {
+		t.Fatalf("expected detached context to have no error, got %v", detached.Err())
+	}
+}
+
+func TestDetachWithTimeoutHasOwnDeadline(t *testing.T) {
+	type key string
+	parent := context.WithValue(context.Background(), key("foo"), "bar")
+
+	detached, cancel := DetachWithTimeout(parent, 10*time.Millisecond)
+	defer cancel()
+
+	if got := detached.Value(key("foo")); got != "bar" {
+		t.Fatalf("expected detached context to inherit parent value, got %v", got)
+	}
+
+	select {
+	case <-detached.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected detached context to time out on its own")
+	}
+
+	if context.Cause(detached) != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", context.Cause(detached))
+	}
+}
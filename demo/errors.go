@@ -0,0 +1,48 @@
+package demo
+
+import (
+	"context"
+	"errors"
+)
+
+// Sentinel causes used with context.WithCancelCause / context.WithTimeoutCause
+// so callers can distinguish *why* a context was torn down instead of getting
+// back the opaque "context canceled" / "context deadline exceeded" strings.
+var (
+	// ErrClientDisconnected is the cause set when ClientCancellationMiddleware
+	// observes the client closing the connection.
+	ErrClientDisconnected = errors.New("client disconnected")
+
+	// ErrManualCancel is the cause set when CancelHandler cancels a request
+	// on behalf of the caller.
+	ErrManualCancel = errors.New("canceled manually via /cancel")
+
+	// ErrProcessDeadline is the cause set when ProcessHandler's configured
+	// timeout elapses before the simulated work completes.
+	ErrProcessDeadline = errors.New("process deadline exceeded")
+
+	// ErrParentCancelled is the cause set when DemoParentCancellation's
+	// parent context is canceled, propagating to its child.
+	ErrParentCancelled = errors.New("parent context canceled")
+
+	// ErrRegistryTTLExpired is the cause set when RequestRegistry evicts an
+	// entry whose TTL elapsed before the request completed on its own.
+	ErrRegistryTTLExpired = errors.New("request registry TTL expired")
+)
+
+// cancelReason maps a cancellation cause to the "reason" label used by
+// demo/metrics's context_cancellations_total counter.
+func cancelReason(cause error) string {
+	switch {
+	case errors.Is(cause, ErrClientDisconnected):
+		return "client_disconnect"
+	case errors.Is(cause, ErrProcessDeadline), errors.Is(cause, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(cause, ErrManualCancel):
+		return "manual"
+	case errors.Is(cause, ErrParentCancelled):
+		return "parent"
+	default:
+		return "unknown"
+	}
+}
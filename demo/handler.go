@@ -5,27 +5,42 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
-	"sync"
 	"time"
 
+	"go-context/demo/metrics"
+
 	"github.com/gin-gonic/gin"
 )
 
-// Global map to store cancellation functions
-var activeRequests = sync.Map{}
+// cancelBody is the optional JSON body accepted by CancelHandler to annotate
+// why a request is being canceled.
+type cancelBody struct {
+	Reason string `json:"reason"`
+}
 
 // CancelHandler demonstrates manual cancellation of in-progress requests.
-// It looks up a request by ID and cancels its context if found.
-// Example: /cancel/req-123456
+// It looks up a request by ID in the package's RequestRegistry and cancels
+// its context, using an optional JSON body {"reason":"..."} as the
+// cancellation cause.
+// Example: /cancel/req-123456 {"reason":"user aborted"}
 func CancelHandler(c *gin.Context) {
 	requestID := c.Param("requestID")
 
-	// Get and call the cancel function
-	if cancelFunc, ok := activeRequests.Load(requestID); ok {
-		cancelFunc.(context.CancelFunc)()
+	var body cancelBody
+	// The reason is optional, so ignore malformed or empty bodies.
+	_ = c.ShouldBindJSON(&body)
+
+	cause := error(ErrManualCancel)
+	if body.Reason != "" {
+		cause = fmt.Errorf("%w: %s", ErrManualCancel, body.Reason)
+	}
+
+	if defaultRegistry.Cancel(requestID, cause) {
+		metrics.ContextCancellations.WithLabelValues("manual").Inc()
 		c.JSON(http.StatusOK, gin.H{
 			"message":   "Request canceled",
 			"requestID": requestID,
+			"cause":     cause.Error(),
 		})
 		return
 	}
@@ -59,7 +74,7 @@ func ProcessHandler(c *gin.Context) {
 	}
 
 	// Create a new context with the timeout
-	ctx, cancel := context.WithTimeout(c.Request.Context(), duration)
+	ctx, cancel := context.WithTimeoutCause(c.Request.Context(), duration, ErrProcessDeadline)
 	defer cancel()
 
 	// Simulate work in a goroutine
@@ -91,12 +106,15 @@ func ProcessHandler(c *gin.Context) {
 			"sleepTime": sleepTime,
 		})
 	case <-ctx.Done():
-		if ctx.Err() == context.DeadlineExceeded {
+		cause := context.Cause(ctx)
+		metrics.ContextCancellations.WithLabelValues(cancelReason(cause)).Inc()
+		if cause == ErrProcessDeadline {
 			c.JSON(http.StatusRequestTimeout, gin.H{
 				"error":     "timeout",
 				"requestID": requestID,
 				"timestamp": timestamp,
 				"message":   "Process timed out",
+				"cause":     cause.Error(),
 			})
 		} else {
 			c.JSON(http.StatusGatewayTimeout, gin.H{
@@ -104,6 +122,7 @@ func ProcessHandler(c *gin.Context) {
 				"requestID": requestID,
 				"timestamp": timestamp,
 				"message":   "Request was canceled",
+				"cause":     cause.Error(),
 			})
 		}
 	}
@@ -117,18 +136,20 @@ func DemoParentCancellation(c *gin.Context) {
 	timestamp, _ := c.Get("timestamp")
 
 	// Create parent context with cancellation
-	parentCtx, parentCancel := context.WithCancel(context.Background())
+	parentCtx, parentCancel := context.WithCancelCause(context.Background())
 
 	// Simulate canceling the parent after some time (1-3 seconds)
+	logger := LoggerFrom(c.Request.Context())
+
 	triggerTime := time.Duration(1+rand.Intn(3)) * time.Second
 	go func() {
 		time.Sleep(triggerTime)
-		fmt.Printf("Request %v: Parent context canceled after %v\n", requestID, triggerTime)
-		parentCancel()
+		logger.Info("Parent context canceled", "after", triggerTime, "requestID", requestID)
+		parentCancel(ErrParentCancelled)
 	}()
 
 	// Child context inherits from parent with a longer timeout
-	childCtx, childCancel := context.WithTimeout(parentCtx, 10*time.Second)
+	childCtx, childCancel := context.WithTimeoutCause(parentCtx, 10*time.Second, ErrProcessDeadline)
 	defer childCancel()
 
 	// Simulate work in a goroutine
@@ -141,7 +162,7 @@ func DemoParentCancellation(c *gin.Context) {
 
 		select {
 		case <-childCtx.Done():
-			errChan <- childCtx.Err()
+			errChan <- context.Cause(childCtx)
 			return
 		case <-time.After(sleepTime):
 			resultChan <- sleepTime.String()
@@ -161,8 +182,10 @@ func DemoParentCancellation(c *gin.Context) {
 			"parentDelay": triggerTime.String(),
 		})
 	case err := <-errChan:
+		metrics.ContextCancellations.WithLabelValues(cancelReason(err)).Inc()
+
 		message := "Process failed"
-		if err == context.Canceled {
+		if err == ErrParentCancelled {
 			message = "Process was canceled by parent context"
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -170,6 +193,7 @@ func DemoParentCancellation(c *gin.Context) {
 			"requestID":   requestID,
 			"timestamp":   timestamp,
 			"message":     message,
+			"cause":       err.Error(),
 			"parentDelay": triggerTime.String(),
 		})
 	}
@@ -218,7 +242,9 @@ func FireAndForgetHandler(c *gin.Context) {
 	requestID, _ := c.Get("requestID")
 	timestamp, _ := c.Get("timestamp")
 
-	ctx := context.WithValue(context.Background(), "requestID", requestID)
+	// Detach so the goroutine keeps the requestID/logger but survives the
+	// request finishing
+	ctx := Detach(c.Request.Context())
 	SafeFireAndForget(ctx)
 
 	c.JSON(http.StatusOK, gin.H{
@@ -229,16 +255,18 @@ func FireAndForgetHandler(c *gin.Context) {
 	})
 }
 
+// BrokenFireAndForgetHandler demonstrates a fire-and-forget bug: it derives
+// its background context straight from
+// c.Request.Context() instead of demo.Detach, so once the request completes
+// and that context is canceled, longRunningOperation observes ctx.Done()
+// well after the HTTP response has already been sent — the leak
+// goroutine_leaks_suspected_total exists to catch.
+// Do not copy this pattern — see FireAndForgetHandler for the fix.
 func BrokenFireAndForgetHandler(c *gin.Context) {
 	requestID, _ := c.Get("requestID")
 	timestamp, _ := c.Get("timestamp")
 
-	// Create a context based on the gin context and we close it end of the function
-	ctx, cancel := context.WithCancel(c.Request.Context())
-	ctx = context.WithValue(ctx, "requestID", requestID)
-	defer cancel()
-
-	SafeFireAndForget(ctx)
+	SafeFireAndForget(c.Request.Context())
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":    "OK",
@@ -263,9 +291,18 @@ func SetupRoutes(r *gin.Engine) {
 		c.Next()
 	})
 
+	// Attach a request-scoped logger before anything that might log
+	r.Use(LoggingMiddleware)
+
+	// Auto-register every request so it can be listed or canceled via the
+	// /requests and /cancel endpoints below
+	r.Use(RegisterRequestMiddleware())
+
 	// Set up the routes
 	r.GET("/process", ProcessHandler)
-	r.GET("/cancel/:requestID", CancelHandler)
+	r.POST("/cancel/:requestID", CancelHandler)
+	r.GET("/requests", ListRequestsHandler)
+	r.GET("/requests/:requestID", GetRequestHandler)
 	r.GET("/demo-parent-cancel", DemoParentCancellation)
 	r.GET("/never-respond", NeverRespondWithGoContextHandler)
 	r.GET("/never-respond-timeout", NeverRespondWithTimeoutContextHandler)
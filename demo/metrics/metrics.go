@@ -0,0 +1,40 @@
+// Package metrics exposes Prometheus instrumentation for the context
+// lifecycle events the demo package illustrates, so the patterns it shows
+// off (client disconnects, timeouts, manual and parent cancellation,
+// goroutine leaks from fire-and-forget work) can be observed on a real
+// dashboard instead of read off stdout.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ContextCancellations counts context cancellations, labeled by why the
+// context was torn down: "client_disconnect", "timeout", "manual", or
+// "parent", and "unknown" for any other cause.
+var ContextCancellations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "context_cancellations_total",
+	Help: "Total number of context cancellations, labeled by reason.",
+}, []string{"reason"})
+
+// RequestDuration observes how long requests take, labeled by how they
+// ended: "ok", "timeout", or "cancelled".
+var RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "request_duration_seconds",
+	Help: "Request duration in seconds, labeled by outcome.",
+}, []string{"outcome"})
+
+// ActiveRequests reports the number of requests currently in flight.
+var ActiveRequests = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "active_requests",
+	Help: "Number of requests currently in flight.",
+})
+
+// GoroutineLeaksSuspected counts cases where a background goroutine observed
+// its context being done well after the HTTP response it was spawned from
+// had already been sent, a strong signal the goroutine outlived its purpose.
+var GoroutineLeaksSuspected = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "goroutine_leaks_suspected_total",
+	Help: "Incremented when a fire-and-forget operation observes context cancellation after its response was already sent.",
+})
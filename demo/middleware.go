@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"go-context/demo/metrics"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -24,23 +26,72 @@ func RequestInfoMiddleware(c *gin.Context) {
 	c.Next()
 }
 
+// RegisterRequestMiddleware auto-registers every incoming request with the
+// package's RequestRegistry so it shows up in GET /requests and can be
+// canceled via CancelHandler without handlers having to opt in. The entry
+// is removed once the request completes or its TTL expires.
+func RegisterRequestMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetString("requestID")
+
+		ctx, cancel := context.WithCancelCause(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		unregister := defaultRegistry.Register(requestID, c.Request.Method, c.FullPath(), c.ClientIP(), cancel)
+		metrics.ActiveRequests.Inc()
+		start := time.Now()
+
+		defer func() {
+			outcome := requestOutcome(context.Cause(ctx))
+			unregister()
+			cancel(nil)
+
+			metrics.ActiveRequests.Dec()
+			metrics.RequestDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+		}()
+
+		c.Next()
+	}
+}
+
+// requestOutcome maps the cause of a completed request's context (before it
+// is canceled by RegisterRequestMiddleware's own cleanup) to the "outcome"
+// label used by demo/metrics's request_duration_seconds histogram.
+func requestOutcome(cause error) string {
+	if cause == nil {
+		return "ok"
+	}
+
+	switch cancelReason(cause) {
+	case "timeout":
+		return "timeout"
+	default:
+		return "cancelled"
+	}
+}
+
 // ClientCancellationMiddleware handles client disconnection by canceling the request context
 // when the client closes the connection. This allows long-running operations to terminate
 // early if the client is no longer waiting for a response.
 func ClientCancellationMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		parent := c.Request.Context()
+
 		// Create a context that will be canceled when the client disconnects
-		ctx, cancel := context.WithCancel(c.Request.Context())
-		defer cancel()
+		ctx, cancel := context.WithCancelCause(parent)
+		defer cancel(nil)
 
 		// Replace the request context
 		c.Request = c.Request.WithContext(ctx)
 
-		// Monitor for client disconnection
+		// Monitor for client disconnection, distinct from our own context
+		// finishing normally once the handler returns
 		go func() {
 			select {
-			case <-c.Request.Context().Done():
-				cancel()
+			case <-parent.Done():
+				cancel(ErrClientDisconnected)
+				metrics.ContextCancellations.WithLabelValues("client_disconnect").Inc()
+			case <-ctx.Done():
 			}
 		}()
 
@@ -0,0 +1,198 @@
+package demo
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestEntry describes an in-flight request tracked by a RequestRegistry.
+type RequestEntry struct {
+	RequestID string
+	Method    string
+	Path      string
+	ClientIP  string
+	StartedAt time.Time
+
+	cancel context.CancelCauseFunc
+}
+
+// RequestRegistry tracks in-flight requests keyed by requestID so they can be
+// listed, inspected, or canceled out-of-band (e.g. via CancelHandler), and
+// evicts entries automatically once the request completes or a TTL expires.
+type RequestRegistry struct {
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[string]*RequestEntry
+	closed  chan struct{}
+}
+
+// NewRequestRegistry creates a RequestRegistry whose entries are evicted
+// after ttl if the owning request hasn't already completed, and starts its
+// background janitor goroutine. Call Close to stop the janitor.
+func NewRequestRegistry(ttl time.Duration) *RequestRegistry {
+	r := &RequestRegistry{
+		ttl:     ttl,
+		entries: make(map[string]*RequestEntry),
+		closed:  make(chan struct{}),
+	}
+	go r.janitor()
+	return r
+}
+
+// Register tracks a new in-flight request and returns a function the caller
+// must invoke once the request completes, which removes the entry. Calling
+// the returned function more than once is a no-op.
+func (r *RequestRegistry) Register(requestID, method, path, clientIP string, cancel context.CancelCauseFunc) func() {
+	entry := &RequestEntry{
+		RequestID: requestID,
+		Method:    method,
+		Path:      path,
+		ClientIP:  clientIP,
+		StartedAt: time.Now(),
+		cancel:    cancel,
+	}
+
+	r.mu.Lock()
+	r.entries[requestID] = entry
+	r.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.entries, requestID)
+			r.mu.Unlock()
+		})
+	}
+}
+
+// Cancel cancels the in-flight request identified by requestID using cause,
+// reporting whether a matching entry was found.
+func (r *RequestRegistry) Cancel(requestID string, cause error) bool {
+	r.mu.RLock()
+	entry, ok := r.entries[requestID]
+	r.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	entry.cancel(cause)
+	return true
+}
+
+// Get returns the entry for requestID, if it is still in flight.
+func (r *RequestRegistry) Get(requestID string) (RequestEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[requestID]
+	if !ok {
+		return RequestEntry{}, false
+	}
+	return *entry, true
+}
+
+// List returns a snapshot of all currently in-flight requests.
+func (r *RequestRegistry) List() []RequestEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]RequestEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		entries = append(entries, *entry)
+	}
+	return entries
+}
+
+// Close stops the background janitor goroutine. It does not cancel any
+// in-flight requests.
+func (r *RequestRegistry) Close() {
+	close(r.closed)
+}
+
+// janitor periodically sweeps for entries whose TTL has expired.
+func (r *RequestRegistry) janitor() {
+	interval := r.ttl / 2
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.closed:
+			return
+		case <-ticker.C:
+			r.sweep()
+		}
+	}
+}
+
+func (r *RequestRegistry) sweep() {
+	cutoff := time.Now().Add(-r.ttl)
+
+	r.mu.Lock()
+	expired := make([]*RequestEntry, 0)
+	for id, entry := range r.entries {
+		if entry.StartedAt.Before(cutoff) {
+			expired = append(expired, entry)
+			delete(r.entries, id)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, entry := range expired {
+		entry.cancel(ErrRegistryTTLExpired)
+	}
+}
+
+// defaultRegistry is the package-wide registry of in-flight requests, wired
+// up by RegisterRequestMiddleware and queried by ListRequestsHandler,
+// GetRequestHandler, and CancelHandler.
+var defaultRegistry = NewRequestRegistry(5 * time.Minute)
+
+// ListRequestsHandler lists every request currently tracked by the registry.
+// Example: GET /requests
+func ListRequestsHandler(c *gin.Context) {
+	entries := defaultRegistry.List()
+
+	requests := make([]gin.H, 0, len(entries))
+	for _, entry := range entries {
+		requests = append(requests, requestEntryJSON(entry))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"requests": requests})
+}
+
+// GetRequestHandler inspects a single request tracked by the registry.
+// Example: GET /requests/req-123456
+func GetRequestHandler(c *gin.Context) {
+	requestID := c.Param("requestID")
+
+	entry, ok := defaultRegistry.Get(requestID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":     "Request not found",
+			"requestID": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, requestEntryJSON(entry))
+}
+
+func requestEntryJSON(entry RequestEntry) gin.H {
+	return gin.H{
+		"requestID": entry.RequestID,
+		"method":    entry.Method,
+		"path":      entry.Path,
+		"clientIP":  entry.ClientIP,
+		"startedAt": entry.StartedAt.Format(time.RFC3339),
+	}
+}
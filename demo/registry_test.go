@@ -0,0 +1,106 @@
+package demo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestRegistryRegisterAndCancel(t *testing.T) {
+	r := NewRequestRegistry(time.Minute)
+	defer r.Close()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	unregister := r.Register("req-1", "GET", "/process", "127.0.0.1", cancel)
+	defer unregister()
+
+	if _, ok := r.Get("req-1"); !ok {
+		t.Fatalf("expected entry to be registered")
+	}
+
+	if !r.Cancel("req-1", ErrManualCancel) {
+		t.Fatalf("expected Cancel to find req-1")
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected context to be canceled")
+	}
+
+	if context.Cause(ctx) != ErrManualCancel {
+		t.Fatalf("expected cause %v, got %v", ErrManualCancel, context.Cause(ctx))
+	}
+}
+
+func TestRequestRegistryCancelUnknown(t *testing.T) {
+	r := NewRequestRegistry(time.Minute)
+	defer r.Close()
+
+	if r.Cancel("does-not-exist", ErrManualCancel) {
+		t.Fatalf("expected Cancel to report no match for an unknown requestID")
+	}
+}
+
+func TestRequestRegistryUnregisterRemovesEntry(t *testing.T) {
+	r := NewRequestRegistry(time.Minute)
+	defer r.Close()
+
+	_, cancel := context.WithCancelCause(context.Background())
+	unregister := r.Register("req-2", "GET", "/process", "127.0.0.1", cancel)
+	unregister()
+
+	if _, ok := r.Get("req-2"); ok {
+		t.Fatalf("expected entry to be removed after unregister")
+	}
+}
+
+func TestRequestRegistryTTLSweep(t *testing.T) {
+	r := NewRequestRegistry(20 * time.Millisecond)
+	defer r.Close()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	r.Register("req-3", "GET", "/process", "127.0.0.1", cancel)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected TTL sweep to cancel the context")
+	}
+
+	if context.Cause(ctx) != ErrRegistryTTLExpired {
+		t.Fatalf("expected cause %v, got %v", ErrRegistryTTLExpired, context.Cause(ctx))
+	}
+
+	if _, ok := r.Get("req-3"); ok {
+		t.Fatalf("expected entry to be evicted after TTL expiry")
+	}
+}
+
+func TestRequestRegistryConcurrentRegisterCancelComplete(t *testing.T) {
+	r := NewRequestRegistry(50 * time.Millisecond)
+	defer r.Close()
+
+	const n = 100
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			id := "req-concurrent"
+			_, cancel := context.WithCancelCause(context.Background())
+			unregister := r.Register(id, "GET", "/process", "127.0.0.1", cancel)
+
+			r.Cancel(id, ErrManualCancel)
+			r.List()
+			r.Get(id)
+
+			unregister()
+		}(i)
+	}
+
+	wg.Wait()
+}
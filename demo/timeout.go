@@ -0,0 +1,197 @@
+package demo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// timeoutResponseWriter wraps gin.ResponseWriter so writes from the handler
+// goroutine can be buffered and only flushed to the real connection if the
+// handler wins the race against the timeout. Every access that could race
+// with the timeout path — Header() included — goes through mu and a private
+// header map; the real ResponseWriter's header map is only touched once a
+// winner is chosen, mirroring stdlib's http.TimeoutHandler.
+type timeoutResponseWriter struct {
+	gin.ResponseWriter
+	header     http.Header
+	body       *bytes.Buffer
+	mu         *sync.Mutex
+	timedOut   *bool
+	wroteHdr   bool
+	statusCode int
+}
+
+func newTimeoutResponseWriter(w gin.ResponseWriter, mu *sync.Mutex, timedOut *bool) *timeoutResponseWriter {
+	return &timeoutResponseWriter{
+		ResponseWriter: w,
+		header:         make(http.Header),
+		body:           &bytes.Buffer{},
+		mu:             mu,
+		timedOut:       timedOut,
+	}
+}
+
+func (w *timeoutResponseWriter) Header() http.Header {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.header
+}
+
+func (w *timeoutResponseWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if *w.timedOut || w.wroteHdr {
+		return
+	}
+	w.wroteHdr = true
+	w.statusCode = code
+}
+
+func (w *timeoutResponseWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if *w.timedOut {
+		// Discard silently to avoid "superfluous response.WriteHeader" panics.
+		return len(data), nil
+	}
+	return w.body.Write(data)
+}
+
+func (w *timeoutResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// TimeoutOptions configures TimeoutMiddleware.
+type TimeoutOptions struct {
+	timeout     time.Duration
+	errHTTPCode int
+	defaultMsg  gin.H
+	callback    func(*http.Request)
+}
+
+// Option configures TimeoutOptions.
+type Option func(*TimeoutOptions)
+
+// WithTimeout sets the wall-clock deadline after which the middleware gives
+// up on the handler and writes the timeout response.
+func WithTimeout(d time.Duration) Option {
+	return func(o *TimeoutOptions) {
+		o.timeout = d
+	}
+}
+
+// WithErrorHTTPCode sets the HTTP status code written when the timeout fires.
+func WithErrorHTTPCode(code int) Option {
+	return func(o *TimeoutOptions) {
+		o.errHTTPCode = code
+	}
+}
+
+// WithDefaultMsg sets the JSON payload written when the timeout fires.
+func WithDefaultMsg(msg gin.H) Option {
+	return func(o *TimeoutOptions) {
+		o.defaultMsg = msg
+	}
+}
+
+// WithCallback registers a function invoked with the original *http.Request
+// whenever the timeout fires, useful for logging or metrics.
+func WithCallback(cb func(*http.Request)) Option {
+	return func(o *TimeoutOptions) {
+		o.callback = cb
+	}
+}
+
+// TimeoutMiddleware returns a gin middleware that enforces a wall-clock
+// deadline on the handler chain, inspired by http.TimeoutHandler. If the
+// deadline expires before the handler finishes writing, the middleware
+// writes a configurable JSON payload and discards any further writes from
+// the still-running handler. The request context is canceled on timeout so
+// well-behaved handlers can unwind early.
+func TimeoutMiddleware(opts ...Option) gin.HandlerFunc {
+	o := &TimeoutOptions{
+		timeout:     5 * time.Second,
+		errHTTPCode: http.StatusGatewayTimeout,
+		defaultMsg:  gin.H{"code": -1, "msg": "handler timeout"},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(c *gin.Context) {
+		req := c.Request
+		ctx, cancel := context.WithTimeout(req.Context(), o.timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		var mu sync.Mutex
+		timedOut := false
+
+		buffered := newTimeoutResponseWriter(c.Writer, &mu, &timedOut)
+		c.Writer = buffered
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+			mu.Lock()
+			defer mu.Unlock()
+			copyHeader(buffered.ResponseWriter.Header(), buffered.header)
+			if buffered.wroteHdr {
+				buffered.ResponseWriter.WriteHeader(buffered.statusCode)
+			}
+			buffered.ResponseWriter.Write(buffered.body.Bytes())
+		case <-ctx.Done():
+			mu.Lock()
+			timedOut = true
+			mu.Unlock()
+
+			if o.callback != nil {
+				o.callback(req)
+			}
+
+			// Write straight to the real ResponseWriter captured before the
+			// swap, never through c.Writer/c.AbortWithStatusJSON: the
+			// handler goroutine is still running and may still be mutating
+			// c's fields, so touching them here would race. buffered now
+			// discards everything the straggling handler writes.
+			writeTimeoutResponse(buffered.ResponseWriter, o.errHTTPCode, o.defaultMsg)
+		}
+	}
+}
+
+// copyHeader copies every header from src into dst, used to hand off the
+// handler's buffered headers to the real ResponseWriter once it's known to
+// have won the race against the timeout.
+func copyHeader(dst, src http.Header) {
+	for k, vv := range src {
+		dst[k] = vv
+	}
+}
+
+// writeTimeoutResponse writes the timeout payload directly to w, bypassing
+// gin.Context entirely so it can be called safely while the timed-out
+// handler is still running in its own goroutine.
+func writeTimeoutResponse(w gin.ResponseWriter, code int, payload gin.H) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		body = []byte(`{"code":-1,"msg":"handler timeout"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	w.Write(body)
+}
@@ -0,0 +1,41 @@
+package demo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeoutMiddlewareDiscardsLateWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handlerDone := make(chan struct{})
+
+	r := gin.New()
+	r.Use(TimeoutMiddleware(WithTimeout(20 * time.Millisecond)))
+	r.GET("/slow", func(c *gin.Context) {
+		defer close(handlerDone)
+		time.Sleep(100 * time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"late": true})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status %d, got %d", http.StatusGatewayTimeout, rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "late") {
+		t.Fatalf("expected late write to be discarded, got body %q", rec.Body.String())
+	}
+
+	// Let the straggling handler finish before the test exits so it doesn't
+	// write after the recorder is torn down.
+	<-handlerDone
+}
@@ -2,9 +2,10 @@ package demo
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
 	"time"
+
+	"go-context/demo/metrics"
 )
 
 // LockResponse demonstrates a long-running operation that has a locked method on defer
@@ -13,7 +14,7 @@ func LockResponse(ctx context.Context) {
 		onlyReturnWhenContextCancelled(ctx)
 	}()
 
-	fmt.Println("This line will be printed")
+	LoggerFrom(ctx).Info("This line will be printed")
 }
 
 func SafeFireAndForget(ctx context.Context) {
@@ -23,20 +24,26 @@ func SafeFireAndForget(ctx context.Context) {
 
 // LongRunningOperation simulates a long-running operation that takes 2 minutes to complete.
 func longRunningOperation(ctx context.Context) {
-	fmt.Println("Starting long-running operation for request_id: ", ctx.Value("requestID"))
+	logger := LoggerFrom(ctx)
+	logger.Info("Starting long-running operation")
 
 	// This takes can randomly fail
 	if rand.Intn(10) < 2 {
-		fmt.Println("Operation failed for request_id: ", ctx.Value("requestID"))
+		logger.Warn("Operation failed")
 		return
 	}
 
 	select {
 	case <-ctx.Done():
-		fmt.Println("Operation canceled for request_id: ", ctx.Value("requestID"))
+		// longRunningOperation only ever runs in a fire-and-forget goroutine
+		// whose handler responds without waiting for it, so observing its
+		// context done here means whatever called it (the request, a parent)
+		// is gone and this goroutine is the last one left holding it.
+		logger.Info("Operation canceled", "cause", context.Cause(ctx))
+		metrics.GoroutineLeaksSuspected.Inc()
 		return
 	case <-time.After(2 * time.Minute):
-		fmt.Println("Operation completed for request_id: ", ctx.Value("requestID"))
+		logger.Info("Operation completed")
 		return
 	}
 }
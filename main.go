@@ -2,10 +2,12 @@ package main
 
 import (
 	"net/http"
+	"time"
 
 	"go-context/demo"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
@@ -21,6 +23,13 @@ func main() {
 	// Add the client cancellation middleware
 	r.Use(demo.ClientCancellationMiddleware())
 
+	// Guard every request with a wall-clock deadline so a stuck handler can't
+	// hang the connection forever
+	r.Use(demo.TimeoutMiddleware(
+		demo.WithTimeout(8*time.Second),
+		demo.WithErrorHTTPCode(http.StatusGatewayTimeout),
+	))
+
 	// Define a route that responds to a GET request at /ping
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -31,6 +40,9 @@ func main() {
 	// Setup demo endpoints
 	demo.SetupRoutes(r)
 
+	// Expose context lifecycle metrics for scraping
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// Run the server on port 8080
 	r.Run(":8080")
 }
@@ -0,0 +1,7 @@
+package process
+
+import "errors"
+
+// ErrProcessDeadline is the cause set on ProcessHandler's context when its
+// configured timeout elapses before the simulated work completes.
+var ErrProcessDeadline = errors.New("process deadline exceeded")
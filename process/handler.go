@@ -28,7 +28,7 @@ func ProcessHandler(c *gin.Context) {
 	}
 
 	// Create a context with the specified timeout
-	ctx, cancel := context.WithTimeout(c.Request.Context(), duration)
+	ctx, cancel := context.WithTimeoutCause(c.Request.Context(), duration, ErrProcessDeadline)
 	defer cancel()
 
 	// Channel to signal completion of our "work"
@@ -54,21 +54,23 @@ func ProcessHandler(c *gin.Context) {
 		})
 	case <-ctx.Done():
 		// Context cancelled or timed out
-		err := ctx.Err()
-		if err == context.DeadlineExceeded {
+		cause := context.Cause(ctx)
+		if cause == ErrProcessDeadline {
 			c.JSON(http.StatusGatewayTimeout, gin.H{
 				"error":     "Request timed out",
 				"requestID": requestID,
 				"timestamp": timestamp,
 				"duration":  timeoutStr,
 				"message":   "The operation took longer than the specified timeout",
+				"cause":     cause.Error(),
 			})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":     "Request cancelled",
 				"requestID": requestID,
 				"timestamp": timestamp,
-				"message":   err.Error(),
+				"message":   cause.Error(),
+				"cause":     cause.Error(),
 			})
 		}
 	}